@@ -0,0 +1,92 @@
+// Command dither reads an image, dithers it, and writes the result. It is a
+// thin wrapper over the imageio and dither packages so the dithering core
+// stays usable as a library independent of this CLI.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/leepav/disering_test/dither"
+	"github.com/leepav/disering_test/imageio"
+)
+
+func main() {
+	inPath := flag.String("in", "", "path to the input image (jpeg, png, gif, bmp, tiff, or webp)")
+	outPath := flag.String("out", "", "path to the output image; extension selects the encoder")
+	mode := flag.String("mode", "mono", "dithering mode: color or mono")
+	method := flag.String("method", "atkinson", "dithering method: atkinson, floyd_steinberg, shtuki, sierra_lite, bayer, blue_noise")
+	paletteFlag := flag.String("palette", "bw", "palette to quantize against: bw, cga16, websafe, or file:path.hex (bayer/blue_noise only support bw)")
+	serpentine := flag.Bool("serpentine", false, "alternate scan direction per row to reduce diagonal artifacts")
+	gammaCorrect := flag.Bool("gamma-correct", false, "quantize and diffuse error in linear light instead of sRGB")
+	resize := flag.String("resize", "", "resize to fit within WIDTHxHEIGHT before dithering, preserving aspect ratio (e.g. 512x512)")
+	brightness := flag.Float64("brightness", 0, "brightness adjustment added to each channel before dithering")
+	contrast := flag.Float64("contrast", 1, "contrast multiplier applied around the midpoint before dithering")
+	gamma := flag.Float64("gamma", 1, "gamma correction applied before dithering (values >1 brighten midtones)")
+	flag.Parse()
+
+	if *inPath == "" || *outPath == "" {
+		fmt.Println("usage: dither -in input.jpg -out output.png [-mode color|mono] [-method atkinson|floyd_steinberg|shtuki|sierra_lite|bayer|blue_noise] [-palette bw|cga16|websafe|file:path.hex] [-resize WxH] [-brightness N] [-contrast N] [-gamma N]")
+		os.Exit(1)
+	}
+
+	img, err := imageio.LoadImage(*inPath)
+	if err != nil {
+		fmt.Printf("Error loading image: %v\n", err)
+		os.Exit(1)
+	}
+
+	palette, err := dither.ParsePalette(*paletteFlag)
+	if err != nil {
+		fmt.Printf("Error parsing -palette: %v\n", err)
+		os.Exit(1)
+	}
+
+	ditherer, err := dither.ByName(*method)
+	if err != nil {
+		fmt.Printf("Error parsing -method: %v\n", err)
+		os.Exit(1)
+	}
+
+	isColor := strings.EqualFold(*mode, "color")
+	opts := dither.DitherOptions{Serpentine: *serpentine, GammaCorrect: *gammaCorrect}
+
+	var pipeline dither.Pipeline
+	if *resize != "" {
+		w, h, err := dither.ParseResize(*resize)
+		if err != nil {
+			fmt.Printf("Error parsing -resize: %v\n", err)
+			os.Exit(1)
+		}
+		pipeline = append(pipeline, dither.ResizeStage(w, h))
+	}
+	if !isColor {
+		pipeline = append(pipeline, dither.LuminanceStage())
+	}
+	if *brightness != 0 || *contrast != 1 || *gamma != 1 {
+		pipeline = append(pipeline, dither.ToneStage(*brightness, *contrast, *gamma))
+	}
+	img = pipeline.Apply(img)
+
+	var ditheredImg image.Image
+	if edd, ok := ditherer.(dither.ErrorDiffusionDitherer); ok {
+		matrix, divisor := edd.Matrix()
+		ditheredImg = dither.DitherWithPalette(img, palette, matrix, divisor, isColor, opts)
+	} else {
+		if *paletteFlag != "bw" {
+			fmt.Printf("Error: -method %s only supports -palette bw; threshold-matrix dithering against an arbitrary palette isn't implemented\n", *method)
+			os.Exit(1)
+		}
+		ditheredImg = ditherer.Dither(img, isColor, opts)
+	}
+
+	if err := imageio.SaveImage(*outPath, ditheredImg); err != nil {
+		fmt.Printf("Error saving image: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Dithered image saved as %s\n", *outPath)
+}
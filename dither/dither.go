@@ -0,0 +1,348 @@
+// Package dither implements the error-diffusion and ordered dithering
+// algorithms used by the dither CLI. It is independent of any particular
+// image format or I/O concern so it can be used as a library; see the
+// imageio package for loading/saving images.
+package dither
+
+import (
+	"image"
+	"image/color"
+)
+
+// Ditherer converts an image to a reduced palette, optionally preserving
+// color, and reports a short name for output-file naming. opts configures
+// serpentine scanning and gamma-correct diffusion; ditherers that don't
+// diffuse error (e.g. BayerDitherer, BlueNoiseDitherer) ignore it.
+type Ditherer interface {
+	Dither(img image.Image, isColor bool, opts DitherOptions) image.Image
+	Name() string
+}
+
+// ErrorDiffusionDitherer is implemented by Ditherers that diffuse
+// quantization error to neighboring pixels (as opposed to threshold-matrix
+// ditherers such as BayerDitherer and BlueNoiseDitherer). Callers use it to
+// route diffusion ditherers through DitherWithPalette.
+type ErrorDiffusionDitherer interface {
+	Ditherer
+	Matrix() ([][]int, float64)
+}
+
+type AtkinsonDitherer struct{}
+
+func (ad AtkinsonDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	matrix, divisor := ad.Matrix()
+	if isColor {
+		return ditherColor(img, matrix, divisor, opts)
+	}
+	return ditherMono(img, matrix, divisor, opts)
+}
+
+func (ad AtkinsonDitherer) Matrix() ([][]int, float64) {
+	return [][]int{{0, 0, 1, 1}, {1, 1, 1, 0}, {0, 1, 0, 0}}, 8.0
+}
+
+func (ad AtkinsonDitherer) Name() string {
+	return "atkinson"
+}
+
+type FloydSteinbergDitherer struct{}
+
+func (fsd FloydSteinbergDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	matrix, divisor := fsd.Matrix()
+	if isColor {
+		return ditherColor(img, matrix, divisor, opts)
+	}
+	return ditherMono(img, matrix, divisor, opts)
+}
+
+func (fsd FloydSteinbergDitherer) Matrix() ([][]int, float64) {
+	return [][]int{{0, 0, 7}, {3, 5, 1}}, 16.0
+}
+
+func (fsd FloydSteinbergDitherer) Name() string {
+	return "floyd_steinberg"
+}
+
+type ShtukiDitherer struct{}
+
+func (sd ShtukiDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	matrix, divisor := sd.Matrix()
+	if isColor {
+		return ditherColor(img, matrix, divisor, opts)
+	}
+	return ditherMono(img, matrix, divisor, opts)
+}
+
+func (sd ShtukiDitherer) Matrix() ([][]int, float64) {
+	return [][]int{{0, 0, 0, 8, 4}, {2, 4, 8, 4, 2}, {1, 2, 4, 2, 1}}, 42.0
+}
+
+func (sd ShtukiDitherer) Name() string {
+	return "shtuki"
+}
+
+type SierraLiteDitherer struct{}
+
+func (sld SierraLiteDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	matrix, divisor := sld.Matrix()
+	if isColor {
+		return ditherColor(img, matrix, divisor, opts)
+	}
+	return ditherMono(img, matrix, divisor, opts)
+}
+
+func (sld SierraLiteDitherer) Matrix() ([][]int, float64) {
+	return [][]int{{0, 0, 2}, {1, 1, 0}}, 4.0
+}
+
+func (sld SierraLiteDitherer) Name() string {
+	return "sierra_lite"
+}
+
+// DitherWithPalette performs true vector error-diffusion dithering against an
+// arbitrary palette: at each pixel it finds the perceptually nearest palette
+// entry (CIELAB ΔE) and diffuses the resulting 3-component RGB quantization
+// error to neighboring pixels per matrix/divisor. isColor selects whether the
+// source image is quantized in full color or converted to grayscale first.
+// opts.Serpentine alternates scan direction per row, mirroring matrix on
+// right-to-left rows; opts.GammaCorrect quantizes and diffuses in linear
+// light instead of sRGB.
+func DitherWithPalette(img image.Image, palette color.Palette, matrix [][]int, divisor float64, isColor bool, opts DitherOptions) *image.Paletted {
+	bounds := img.Bounds()
+	out := image.NewPaletted(bounds, palette)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	r := make([][]float64, height)
+	g := make([][]float64, height)
+	b := make([][]float64, height)
+	for i := 0; i < height; i++ {
+		r[i] = make([]float64, width)
+		g[i] = make([]float64, width)
+		b[i] = make([]float64, width)
+	}
+
+	toWork, fromWork := workConversions(opts)
+	center := centerCol(matrix)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			px := img.At(bounds.Min.X+x, bounds.Min.Y+y)
+			if isColor {
+				cr, cg, cb, _ := px.RGBA()
+				r[y][x] = toWork(uint8(cr >> 8))
+				g[y][x] = toWork(uint8(cg >> 8))
+				b[y][x] = toWork(uint8(cb >> 8))
+			} else {
+				gray := color.GrayModel.Convert(px).(color.Gray).Y
+				r[y][x] = toWork(gray)
+				g[y][x] = toWork(gray)
+				b[y][x] = toWork(gray)
+			}
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		rightToLeft := opts.Serpentine && y%2 == 1
+
+		start, end, step := 0, width, 1
+		if rightToLeft {
+			start, end, step = width-1, -1, -1
+		}
+
+		for x := start; x != end; x += step {
+			oldR, oldG, oldB := r[y][x], g[y][x], b[y][x]
+			oldColor := color.RGBA{R: fromWork(oldR), G: fromWork(oldG), B: fromWork(oldB), A: 255}
+
+			nearest, idx := findClosestPaletteColor(oldColor, palette)
+			out.SetColorIndex(bounds.Min.X+x, bounds.Min.Y+y, uint8(idx))
+
+			nr, ng, nb, _ := nearest.RGBA()
+			errR := oldR - toWork(uint8(nr>>8))
+			errG := oldG - toWork(uint8(ng>>8))
+			errB := oldB - toWork(uint8(nb>>8))
+
+			for i := 0; i < len(matrix); i++ {
+				for j := 0; j < len(matrix[i]); j++ {
+					weight := matrix[i][j]
+					if weight == 0 {
+						continue
+					}
+					dx := j - center
+					if rightToLeft {
+						dx = -dx
+					}
+					nx, ny := x+dx, y+i
+					if nx < 0 || nx >= width || ny < 0 || ny >= height {
+						continue
+					}
+					factor := float64(weight) / divisor
+					r[ny][nx] += errR * factor
+					g[ny][nx] += errG * factor
+					b[ny][nx] += errB * factor
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+func ditherColor(img image.Image, matrix [][]int, divisor float64, opts DitherOptions) *image.RGBA {
+	bounds := img.Bounds()
+	ditheredImg := image.NewRGBA(bounds)
+
+	red := extractChannel(img, 0)
+	green := extractChannel(img, 1)
+	blue := extractChannel(img, 2)
+
+	ditheredRed := ditherChannel(red, matrix, divisor, opts)
+	ditheredGreen := ditherChannel(green, matrix, divisor, opts)
+	ditheredBlue := ditherChannel(blue, matrix, divisor, opts)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r := ditheredRed.GrayAt(x, y).Y
+			g := ditheredGreen.GrayAt(x, y).Y
+			b := ditheredBlue.GrayAt(x, y).Y
+			ditheredImg.SetRGBA(x, y, color.RGBA{R: r, G: g, B: b, A: 255})
+		}
+	}
+
+	return ditheredImg
+}
+
+func ditherMono(img image.Image, matrix [][]int, divisor float64, opts DitherOptions) *image.Gray {
+	bounds := img.Bounds()
+	grayImg := image.NewGray(bounds)
+
+	toWork, fromWork := workConversions(opts)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	work := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		work[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			gray := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y
+			work[y][x] = toWork(gray)
+		}
+	}
+
+	center := centerCol(matrix)
+
+	for y := 0; y < height; y++ {
+		rightToLeft := opts.Serpentine && y%2 == 1
+
+		start, end, step := 0, width, 1
+		if rightToLeft {
+			start, end, step = width-1, -1, -1
+		}
+
+		for x := start; x != end; x += step {
+			oldPixel := fromWork(work[y][x])
+			newColor, _ := findClosestPaletteColor(color.Gray{Y: oldPixel}, BWPalette)
+			newPixel := color.GrayModel.Convert(newColor).(color.Gray).Y
+			grayImg.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{Y: newPixel})
+
+			quantError := work[y][x] - toWork(newPixel)
+
+			for i := 0; i < len(matrix); i++ {
+				for j := 0; j < len(matrix[i]); j++ {
+					weight := matrix[i][j]
+					if weight == 0 {
+						continue
+					}
+					dx := j - center
+					if rightToLeft {
+						dx = -dx
+					}
+					nx, ny := x+dx, y+i
+					if nx < 0 || nx >= width || ny < 0 || ny >= height {
+						continue
+					}
+					work[ny][nx] += quantError * (float64(weight) / divisor)
+				}
+			}
+		}
+	}
+
+	return grayImg
+}
+
+func extractChannel(img image.Image, channelIndex int) *image.Gray {
+	bounds := img.Bounds()
+	grayImg := image.NewGray(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			var value uint8
+			switch channelIndex {
+			case 0:
+				value = uint8(r >> 8) // Red channel
+			case 1:
+				value = uint8(g >> 8) // Green channel
+			case 2:
+				value = uint8(b >> 8) // Blue channel
+			}
+			grayImg.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+
+	return grayImg
+}
+
+func ditherChannel(channel *image.Gray, matrix [][]int, divisor float64, opts DitherOptions) *image.Gray {
+	bounds := channel.Bounds()
+	ditheredChannel := image.NewGray(bounds)
+
+	toWork, fromWork := workConversions(opts)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	work := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		work[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			work[y][x] = toWork(channel.GrayAt(bounds.Min.X+x, bounds.Min.Y+y).Y)
+		}
+	}
+
+	center := centerCol(matrix)
+
+	for y := 0; y < height; y++ {
+		rightToLeft := opts.Serpentine && y%2 == 1
+
+		start, end, step := 0, width, 1
+		if rightToLeft {
+			start, end, step = width-1, -1, -1
+		}
+
+		for x := start; x != end; x += step {
+			oldPixel := fromWork(work[y][x])
+			newColor, _ := findClosestPaletteColor(color.Gray{Y: oldPixel}, BWPalette)
+			newPixel := color.GrayModel.Convert(newColor).(color.Gray).Y
+			ditheredChannel.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{Y: newPixel})
+
+			quantError := work[y][x] - toWork(newPixel)
+
+			for i := 0; i < len(matrix); i++ {
+				for j := 0; j < len(matrix[i]); j++ {
+					weight := matrix[i][j]
+					if weight == 0 {
+						continue
+					}
+					dx := j - center
+					if rightToLeft {
+						dx = -dx
+					}
+					nx, ny := x+dx, y+i
+					if nx < 0 || nx >= width || ny < 0 || ny >= height {
+						continue
+					}
+					work[ny][nx] += quantError * (float64(weight) / divisor)
+				}
+			}
+		}
+	}
+
+	return ditheredChannel
+}
@@ -0,0 +1,64 @@
+package dither
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCenterColMatchesEachMatrix(t *testing.T) {
+	fsMatrix, _ := FloydSteinbergDitherer{}.Matrix()
+	sierraMatrix, _ := SierraLiteDitherer{}.Matrix()
+	atkinsonMatrix, _ := AtkinsonDitherer{}.Matrix()
+	shtukiMatrix, _ := ShtukiDitherer{}.Matrix()
+
+	cases := []struct {
+		name   string
+		matrix [][]int
+		want   int
+	}{
+		{"floyd_steinberg", fsMatrix, 1},
+		{"sierra_lite", sierraMatrix, 1},
+		{"atkinson", atkinsonMatrix, 1},
+		{"shtuki", shtukiMatrix, 2},
+	}
+	for _, c := range cases {
+		if got := centerCol(c.matrix); got != c.want {
+			t.Errorf("centerCol(%s) = %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+// TestFloydSteinbergConservesError dithers a constant gray field and checks
+// that the fraction of output white pixels tracks the input intensity, which
+// only holds if quantization error is actually conserved (propagated to the
+// right neighbor column, not discarded onto an already-emitted pixel).
+func TestFloydSteinbergConservesError(t *testing.T) {
+	const size = 64
+	const gray = 100
+
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetGray(x, y, color.Gray{Y: gray})
+		}
+	}
+
+	out := FloydSteinbergDitherer{}.Dither(img, false, DitherOptions{}).(*image.Gray)
+
+	white := 0
+	total := size * size
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if out.GrayAt(x, y).Y == 255 {
+				white++
+			}
+		}
+	}
+
+	got := float64(white) / float64(total)
+	want := gray / 255.0
+	if diff := got - want; diff > 0.03 || diff < -0.03 {
+		t.Errorf("white fraction = %.3f, want close to %.3f (input intensity)", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package dither
+
+import "math"
+
+// DitherOptions configures behavior shared by all ErrorDiffusionDitherers:
+//
+//   - Serpentine alternates left-to-right and right-to-left scanning per
+//     row (mirroring the diffusion pattern, via centerCol, on right-to-left
+//     rows), which reduces the diagonal "worm" artifacts plain raster-order
+//     Floyd-Steinberg style diffusion produces on gradients.
+//   - GammaCorrect quantizes and propagates error in linear light instead of
+//     perceptual sRGB, which improves results on photographic input.
+type DitherOptions struct {
+	Serpentine   bool
+	GammaCorrect bool
+}
+
+// workConversions returns the toWork/fromWork helpers for the pixel space
+// error diffusion operates in: linear light scaled to [0,255] when
+// GammaCorrect is set, otherwise plain sRGB. fromWork always clamps to a
+// valid 8-bit value.
+func workConversions(opts DitherOptions) (toWork func(uint8) float64, fromWork func(float64) uint8) {
+	clamp := func(v float64) float64 {
+		return math.Min(255, math.Max(0, v))
+	}
+	if opts.GammaCorrect {
+		return func(v uint8) float64 { return srgbToLinear(v) * 255.0 },
+			func(v float64) uint8 { return linearToSRGB(clamp(v) / 255.0) }
+	}
+	return func(v uint8) float64 { return float64(v) },
+		func(v float64) uint8 { return uint8(clamp(v)) }
+}
+
+// centerCol returns the column a diffusion matrix's weights are positioned
+// relative to: the pixel just written sits conceptually at this column, with
+// weights at columns to its right (same row) and on either side (following
+// rows). FS/Sierra-Lite/Atkinson's 3-4 wide matrices center on column 1;
+// Shtuki's 5-wide matrix centers on column 2. On right-to-left serpentine
+// rows, negating a weight's column offset from this center mirrors the
+// diffusion pattern without needing to physically reverse the matrix (which
+// only stays aligned for odd-width matrices).
+func centerCol(matrix [][]int) int {
+	return (len(matrix[0]) - 1) / 2
+}
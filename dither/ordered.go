@@ -0,0 +1,289 @@
+package dither
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// bayerM2 is the base 2x2 Bayer matrix all larger Bayer matrices recurse from.
+var bayerM2 = [][]int{{0, 2}, {3, 1}}
+
+// bayerMatrixInt recursively builds an NxN Bayer matrix (N a power of two)
+// with unnormalized integer values in [0, N*N), per
+// M_{2n}[i,j] = 4*M_n[i mod n, j mod n] + M2[i/n, j/n].
+func bayerMatrixInt(n int) [][]int {
+	if n <= 2 {
+		return bayerM2
+	}
+	half := n / 2
+	prev := bayerMatrixInt(half)
+	m := make([][]int, n)
+	for i := 0; i < n; i++ {
+		m[i] = make([]int, n)
+		for j := 0; j < n; j++ {
+			m[i][j] = 4*prev[i%half][j%half] + bayerM2[i/half][j/half]
+		}
+	}
+	return m
+}
+
+// bayerMatrix builds an NxN Bayer threshold matrix normalized to [0, 255].
+func bayerMatrix(n int) [][]uint8 {
+	raw := bayerMatrixInt(n)
+	scale := 255.0 / float64(n*n)
+	matrix := make([][]uint8, n)
+	for i := range raw {
+		matrix[i] = make([]uint8, n)
+		for j, v := range raw[i] {
+			matrix[i][j] = uint8(float64(v) * scale)
+		}
+	}
+	return matrix
+}
+
+// BayerDitherer is an ordered (threshold-matrix) ditherer: each pixel is
+// compared against a tiled NxN Bayer matrix with no error diffusion, so rows
+// can be processed independently and in parallel.
+type BayerDitherer struct {
+	N int
+}
+
+// Dither ignores opts: there's no error to diffuse, and a fixed threshold
+// matrix has no scan direction for serpentine to alternate.
+func (bd BayerDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	return ditherOrdered(img, bayerMatrix(bd.N), bd.N, isColor)
+}
+
+func (bd BayerDitherer) Name() string {
+	return fmt.Sprintf("bayer%d", bd.N)
+}
+
+// voidAndClusterSigma is the standard deviation of the Gaussian energy
+// filter used to judge how "crowded" a cell is, per Ulichney's void-and-
+// cluster method.
+const voidAndClusterSigma = 1.5
+
+// toroidalDelta returns the shorter distance between coordinates a and b on
+// a size-n ring, so the energy filter wraps around the mask edges the same
+// way the mask itself is tiled across an image.
+func toroidalDelta(a, b, n int) int {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	if d > n-d {
+		d = n - d
+	}
+	return d
+}
+
+// clusterEnergy sums a Gaussian falloff from idx to every other cell in on
+// whose membership equals measureOn, using toroidal distance. A high value
+// for an "on" cell means it sits in a tight cluster of other "on" cells; a
+// low value for an "off" cell means it sits in a large void of other "off"
+// cells.
+func clusterEnergy(on []bool, idx, n int, measureOn bool) float64 {
+	x, y := idx%n, idx/n
+	var e float64
+	for j, v := range on {
+		if j == idx || v != measureOn {
+			continue
+		}
+		dx := toroidalDelta(x, j%n, n)
+		dy := toroidalDelta(y, j/n, n)
+		e += math.Exp(-float64(dx*dx+dy*dy) / (2 * voidAndClusterSigma * voidAndClusterSigma))
+	}
+	return e
+}
+
+// extremeEnergyCell scans the cells of on whose membership equals want and
+// returns the one with the largest (if max) or smallest (if !max) cluster
+// energy measured against measureOn.
+func extremeEnergyCell(on []bool, n int, want, measureOn, max bool) int {
+	best, bestE := -1, 0.0
+	if !max {
+		bestE = math.Inf(1)
+	} else {
+		bestE = math.Inf(-1)
+	}
+	for idx, v := range on {
+		if v != want {
+			continue
+		}
+		e := clusterEnergy(on, idx, n, measureOn)
+		if (max && e > bestE) || (!max && e < bestE) {
+			bestE, best = e, idx
+		}
+	}
+	return best
+}
+
+// voidAndClusterMask generates an n x n blue-noise threshold mask using
+// Ulichney's void-and-cluster method: an initial binary pattern is grown one
+// point at a time into the current largest void, then ranked in three
+// passes — tightest clusters removed first, voids filled back in until the
+// pattern is half full, then the remaining voids' tightest clusters filled
+// in last — so that every local neighborhood of the final mask has an even
+// mix of low and high thresholds. This is what gives blue noise its
+// non-repeating, evenly-spread dither pattern, unlike white noise (an
+// unstructured shuffle), which clumps and leaves gaps at random.
+func voidAndClusterMask(n int) [][]uint8 {
+	total := n * n
+	on := make([]bool, total)
+	ranks := make([]int, total)
+
+	seedCount := total / 10
+	if seedCount < 1 {
+		seedCount = 1
+	}
+	on[(n/2)*n+n/2] = true
+	for placed := 1; placed < seedCount; placed++ {
+		on[extremeEnergyCell(on, n, false, true, false)] = true
+	}
+	prototype := append([]bool(nil), on...)
+
+	// Phase 1: rank the prototype's "on" cells from tightest cluster to
+	// loosest, freeing them back up as each is ranked.
+	rank := 0
+	for onCount := seedCount; onCount > 0; onCount-- {
+		idx := extremeEnergyCell(on, n, true, true, true)
+		ranks[idx] = rank
+		on[idx] = false
+		rank++
+	}
+
+	// Phase 2: starting over from the prototype, fill the largest void each
+	// time until the mask is half full.
+	on = prototype
+	for onCount := seedCount; onCount < total/2; onCount++ {
+		idx := extremeEnergyCell(on, n, false, true, false)
+		on[idx] = true
+		ranks[idx] = rank
+		rank++
+	}
+
+	// Phase 3: continue to full by treating the remaining "off" cells as
+	// the pattern of interest and filling their tightest cluster first.
+	for onCount := total / 2; onCount < total; onCount++ {
+		idx := extremeEnergyCell(on, n, false, false, true)
+		on[idx] = true
+		ranks[idx] = rank
+		rank++
+	}
+
+	scale := 255.0 / float64(total)
+	mask := make([][]uint8, n)
+	for y := 0; y < n; y++ {
+		mask[y] = make([]uint8, n)
+		for x := 0; x < n; x++ {
+			mask[y][x] = uint8(float64(ranks[y*n+x]) * scale)
+		}
+	}
+	return mask
+}
+
+// DefaultBlueNoiseMask is the 8x8 mask BlueNoiseDitherer falls back to when
+// constructed without one.
+var DefaultBlueNoiseMask = voidAndClusterMask(8)
+
+// BlueNoiseDitherer is an ordered ditherer that tiles a precomputed
+// void-and-cluster mask instead of a Bayer matrix. Like BayerDitherer it does
+// no error diffusion, so rows dither independently and in parallel.
+type BlueNoiseDitherer struct {
+	Mask [][]uint8
+}
+
+// Dither ignores opts for the same reason as BayerDitherer.Dither.
+func (bnd BlueNoiseDitherer) Dither(img image.Image, isColor bool, opts DitherOptions) image.Image {
+	mask := bnd.Mask
+	if mask == nil {
+		mask = DefaultBlueNoiseMask
+	}
+	return ditherOrdered(img, mask, len(mask), isColor)
+}
+
+func (bnd BlueNoiseDitherer) Name() string {
+	return "blue_noise"
+}
+
+// thresholdChannel quantizes a single 8-bit channel against a tiled
+// threshold value: above the threshold maps to white, at or below to black.
+func thresholdChannel(v, threshold uint8) uint8 {
+	if v > threshold {
+		return 255
+	}
+	return 0
+}
+
+// runRowWorkers splits the rows of bounds across a pool of goroutines (one
+// per CPU, capped at the row count) and runs rowFunc for each row. Ordered dithering has no
+// serial dependency between pixels, so rows can be processed concurrently.
+func runRowWorkers(bounds image.Rectangle, rowFunc func(y int)) {
+	height := bounds.Dy()
+	workers := runtime.NumCPU()
+	if workers > height {
+		workers = height
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	rows := make(chan int, height)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		rows <- y
+	}
+	close(rows)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for y := range rows {
+				rowFunc(y)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// ditherOrdered applies a threshold matrix (Bayer or blue-noise) to img,
+// tiling it across the image and thresholding each pixel independently. Rows
+// are processed by a worker pool since there is no diffusion dependency
+// between pixels.
+func ditherOrdered(img image.Image, matrix [][]uint8, n int, isColor bool) image.Image {
+	bounds := img.Bounds()
+
+	if isColor {
+		out := image.NewRGBA(bounds)
+		runRowWorkers(bounds, func(y int) {
+			row := (y - bounds.Min.Y) % n
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				threshold := matrix[row][(x-bounds.Min.X)%n]
+				r, g, b, _ := img.At(x, y).RGBA()
+				out.SetRGBA(x, y, color.RGBA{
+					R: thresholdChannel(uint8(r>>8), threshold),
+					G: thresholdChannel(uint8(g>>8), threshold),
+					B: thresholdChannel(uint8(b>>8), threshold),
+					A: 255,
+				})
+			}
+		})
+		return out
+	}
+
+	out := image.NewGray(bounds)
+	runRowWorkers(bounds, func(y int) {
+		row := (y - bounds.Min.Y) % n
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			threshold := matrix[row][(x-bounds.Min.X)%n]
+			gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+			out.SetGray(x, y, color.Gray{Y: thresholdChannel(gray, threshold)})
+		}
+	})
+	return out
+}
@@ -0,0 +1,78 @@
+package dither
+
+import "testing"
+
+func TestBayerMatrixIntBase(t *testing.T) {
+	want := [][]int{{0, 2}, {3, 1}}
+	got := bayerMatrixInt(2)
+	for i := range want {
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("bayerMatrixInt(2)[%d][%d] = %d, want %d", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestBayerMatrixIntRecursion(t *testing.T) {
+	// M4[i][j] = 4*M2[i%2][j%2] + M2[i/2][j/2], per the Bayer recurrence.
+	m2 := bayerMatrixInt(2)
+	m4 := bayerMatrixInt(4)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			want := 4*m2[i%2][j%2] + m2[i/2][j/2]
+			if m4[i][j] != want {
+				t.Errorf("bayerMatrixInt(4)[%d][%d] = %d, want %d", i, j, m4[i][j], want)
+			}
+		}
+	}
+}
+
+func TestBayerMatrixIntAllValuesDistinct(t *testing.T) {
+	m := bayerMatrixInt(4)
+	seen := make(map[int]bool)
+	for _, row := range m {
+		for _, v := range row {
+			if seen[v] {
+				t.Fatalf("duplicate value %d in bayerMatrixInt(4)", v)
+			}
+			seen[v] = true
+		}
+	}
+	if len(seen) != 16 {
+		t.Errorf("bayerMatrixInt(4) has %d distinct values, want 16", len(seen))
+	}
+}
+
+func TestVoidAndClusterMaskIsAPermutation(t *testing.T) {
+	mask := voidAndClusterMask(8)
+	seen := make(map[uint8]bool)
+	for _, row := range mask {
+		if len(row) != 8 {
+			t.Fatalf("row length %d, want 8", len(row))
+		}
+		for _, v := range row {
+			if seen[v] {
+				t.Fatalf("duplicate threshold value %d in voidAndClusterMask(8)", v)
+			}
+			seen[v] = true
+		}
+	}
+	if len(seen) != 64 {
+		t.Errorf("voidAndClusterMask(8) has %d distinct values, want 64", len(seen))
+	}
+}
+
+func TestToroidalDelta(t *testing.T) {
+	cases := []struct{ a, b, n, want int }{
+		{0, 0, 8, 0},
+		{0, 7, 8, 1},
+		{1, 6, 8, 3},
+		{0, 4, 8, 4},
+	}
+	for _, c := range cases {
+		if got := toroidalDelta(c.a, c.b, c.n); got != c.want {
+			t.Errorf("toroidalDelta(%d,%d,%d) = %d, want %d", c.a, c.b, c.n, got, c.want)
+		}
+	}
+}
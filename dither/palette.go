@@ -0,0 +1,192 @@
+package dither
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image/color"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// BWPalette is the default two-level black/white palette used when no
+// -palette flag is given, preserving the historical behavior of this tool.
+var BWPalette = color.Palette{color.Gray{Y: 0}, color.Gray{Y: 255}}
+
+// CGA16Palette is the standard 16-color CGA/EGA palette.
+var CGA16Palette = color.Palette{
+	color.RGBA{0, 0, 0, 255},
+	color.RGBA{0, 0, 170, 255},
+	color.RGBA{0, 170, 0, 255},
+	color.RGBA{0, 170, 170, 255},
+	color.RGBA{170, 0, 0, 255},
+	color.RGBA{170, 0, 170, 255},
+	color.RGBA{170, 85, 0, 255},
+	color.RGBA{170, 170, 170, 255},
+	color.RGBA{85, 85, 85, 255},
+	color.RGBA{85, 85, 255, 255},
+	color.RGBA{85, 255, 85, 255},
+	color.RGBA{85, 255, 255, 255},
+	color.RGBA{255, 85, 85, 255},
+	color.RGBA{255, 85, 255, 255},
+	color.RGBA{255, 255, 85, 255},
+	color.RGBA{255, 255, 255, 255},
+}
+
+// WebSafePalette is the classic 216-color web-safe palette: every channel
+// restricted to the six values 0x00, 0x33, 0x66, 0x99, 0xCC, 0xFF.
+var WebSafePalette = buildWebSafePalette()
+
+func buildWebSafePalette() color.Palette {
+	steps := []uint8{0x00, 0x33, 0x66, 0x99, 0xCC, 0xFF}
+	palette := make(color.Palette, 0, len(steps)*len(steps)*len(steps))
+	for _, r := range steps {
+		for _, g := range steps {
+			for _, b := range steps {
+				palette = append(palette, color.RGBA{r, g, b, 255})
+			}
+		}
+	}
+	return palette
+}
+
+// loadHexPalette reads a palette from a text file containing one "RRGGBB"
+// hex color per line. Blank lines and lines starting with '#' are ignored.
+func loadHexPalette(path string) (color.Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading palette file: %w", err)
+	}
+
+	var palette color.Palette
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "0x")
+		if len(line) != 6 {
+			return nil, fmt.Errorf("invalid hex color %q: expected 6 hex digits", line)
+		}
+		v, err := strconv.ParseUint(line, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex color %q: %w", line, err)
+		}
+		palette = append(palette, color.RGBA{
+			R: uint8(v >> 16),
+			G: uint8(v >> 8),
+			B: uint8(v),
+			A: 255,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading palette file: %w", err)
+	}
+	if len(palette) == 0 {
+		return nil, fmt.Errorf("palette file %q contains no colors", path)
+	}
+	return palette, nil
+}
+
+// ParsePalette resolves a -palette flag value ("bw", "cga16", "websafe", or
+// "file:path.hex") into a color.Palette.
+func ParsePalette(spec string) (color.Palette, error) {
+	switch {
+	case spec == "bw":
+		return BWPalette, nil
+	case spec == "cga16":
+		return CGA16Palette, nil
+	case spec == "websafe":
+		return WebSafePalette, nil
+	case strings.HasPrefix(spec, "file:"):
+		return loadHexPalette(strings.TrimPrefix(spec, "file:"))
+	default:
+		return nil, fmt.Errorf("unknown palette %q: expected bw, cga16, websafe, or file:path.hex", spec)
+	}
+}
+
+// srgbToLinear converts an 8-bit sRGB channel value to linear light.
+func srgbToLinear(v uint8) float64 {
+	c := float64(v) / 255.0
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear-light value in [0,1] back to an 8-bit sRGB
+// channel value.
+func linearToSRGB(c float64) uint8 {
+	c = math.Min(1, math.Max(0, c))
+	var v float64
+	if c <= 0.0031308 {
+		v = c * 12.92
+	} else {
+		v = 1.055*math.Pow(c, 1.0/2.4) - 0.055
+	}
+	return uint8(math.Round(v * 255))
+}
+
+// rgbToXYZ converts 8-bit sRGB, linearized first via srgbToLinear, to CIE
+// 1931 XYZ (D65 white point).
+func rgbToXYZ(r, g, b uint8) (x, y, z float64) {
+	rl, gl, bl := srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+	x = rl*0.4124564 + gl*0.3575761 + bl*0.1804375
+	y = rl*0.2126729 + gl*0.7151522 + bl*0.0721750
+	z = rl*0.0193339 + gl*0.1191920 + bl*0.9503041
+	return
+}
+
+// xyzToLab converts CIE XYZ to CIELAB, using the D65 reference white.
+func xyzToLab(x, y, z float64) (l, a, b float64) {
+	const (
+		xn = 0.95047
+		yn = 1.00000
+		zn = 1.08883
+	)
+	f := func(t float64) float64 {
+		if t > 216.0/24389.0 {
+			return math.Cbrt(t)
+		}
+		return (24389.0/27.0*t + 16.0) / 116.0
+	}
+	fx, fy, fz := f(x/xn), f(y/yn), f(z/zn)
+	l = 116*fy - 16
+	a = 500 * (fx - fy)
+	b = 200 * (fy - fz)
+	return
+}
+
+// colorToLab converts a color.Color to CIELAB.
+func colorToLab(c color.Color) (l, a, b float64) {
+	r, g, bl, _ := c.RGBA()
+	x, y, z := rgbToXYZ(uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+	return xyzToLab(x, y, z)
+}
+
+// deltaE is the CIE76 perceptual color distance between two Lab colors.
+func deltaE(l1, a1, b1, l2, a2, b2 float64) float64 {
+	dl, da, db := l1-l2, a1-a2, b1-b2
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// findClosestPaletteColor returns the entry of palette perceptually nearest
+// to c, measured as CIELAB ΔE, along with its index within the palette.
+func findClosestPaletteColor(c color.Color, palette color.Palette) (color.Color, int) {
+	l1, a1, b1 := colorToLab(c)
+
+	best := 0
+	bestDist := math.Inf(1)
+	for i, p := range palette {
+		l2, a2, b2 := colorToLab(p)
+		d := deltaE(l1, a1, b1, l2, a2, b2)
+		if d < bestDist {
+			bestDist = d
+			best = i
+		}
+	}
+	return palette[best], best
+}
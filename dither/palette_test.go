@@ -0,0 +1,89 @@
+package dither
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestSRGBLinearRoundTrip(t *testing.T) {
+	for v := 0; v <= 255; v++ {
+		got := linearToSRGB(srgbToLinear(uint8(v)))
+		if int(got)-v > 1 || v-int(got) > 1 {
+			t.Errorf("round-trip for %d: got %d, want within 1 of %d", v, got, v)
+		}
+	}
+}
+
+func TestDeltaEIdentical(t *testing.T) {
+	l, a, b := colorToLab(color.RGBA{R: 120, G: 60, B: 200, A: 255})
+	if d := deltaE(l, a, b, l, a, b); d != 0 {
+		t.Errorf("deltaE of a color against itself = %v, want 0", d)
+	}
+}
+
+func TestDeltaEBlackWhiteIsLargerThanBlackGray(t *testing.T) {
+	lBlack, aBlack, bBlack := colorToLab(color.Black)
+	lWhite, aWhite, bWhite := colorToLab(color.White)
+	lGray, aGray, bGray := colorToLab(color.Gray{Y: 64})
+
+	blackWhite := deltaE(lBlack, aBlack, bBlack, lWhite, aWhite, bWhite)
+	blackGray := deltaE(lBlack, aBlack, bBlack, lGray, aGray, bGray)
+	if blackWhite <= blackGray {
+		t.Errorf("ΔE(black,white) = %v, want > ΔE(black,dark gray) = %v", blackWhite, blackGray)
+	}
+}
+
+func TestFindClosestPaletteColorPicksNearest(t *testing.T) {
+	_, idx := findClosestPaletteColor(color.Gray{Y: 10}, BWPalette)
+	if idx != 0 {
+		t.Errorf("nearly-black gray matched index %d, want 0 (black)", idx)
+	}
+	_, idx = findClosestPaletteColor(color.Gray{Y: 250}, BWPalette)
+	if idx != 1 {
+		t.Errorf("nearly-white gray matched index %d, want 1 (white)", idx)
+	}
+}
+
+func TestParsePaletteUnknown(t *testing.T) {
+	if _, err := ParsePalette("not-a-palette"); err == nil {
+		t.Error("ParsePalette(\"not-a-palette\") returned nil error, want error")
+	}
+}
+
+func TestParsePaletteBuiltins(t *testing.T) {
+	cases := map[string]int{
+		"bw":      2,
+		"cga16":   16,
+		"websafe": 216,
+	}
+	for spec, wantLen := range cases {
+		p, err := ParsePalette(spec)
+		if err != nil {
+			t.Fatalf("ParsePalette(%q): %v", spec, err)
+		}
+		if len(p) != wantLen {
+			t.Errorf("ParsePalette(%q) has %d entries, want %d", spec, len(p), wantLen)
+		}
+	}
+}
+
+func TestLinearToSRGBClampsOutOfRange(t *testing.T) {
+	if got := linearToSRGB(-1); got != 0 {
+		t.Errorf("linearToSRGB(-1) = %d, want 0", got)
+	}
+	if got := linearToSRGB(2); got != 255 {
+		t.Errorf("linearToSRGB(2) = %d, want 255", got)
+	}
+}
+
+func TestSRGBToLinearMonotonic(t *testing.T) {
+	prev := -math.MaxFloat64
+	for v := 0; v <= 255; v++ {
+		cur := srgbToLinear(uint8(v))
+		if cur < prev {
+			t.Fatalf("srgbToLinear not monotonic at %d: %v < %v", v, cur, prev)
+		}
+		prev = cur
+	}
+}
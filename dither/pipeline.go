@@ -0,0 +1,147 @@
+package dither
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// Stage is a single preprocessing step applied to an image before dithering,
+// such as resizing or a tone adjustment.
+type Stage func(image.Image) image.Image
+
+// Pipeline is an ordered sequence of Stages run before the Ditherer, e.g. a
+// resize followed by brightness/contrast/gamma correction.
+type Pipeline []Stage
+
+// Apply runs each stage of p over img in order, threading the result of one
+// stage into the next.
+func (p Pipeline) Apply(img image.Image) image.Image {
+	for _, stage := range p {
+		img = stage(img)
+	}
+	return img
+}
+
+// ParseResize parses a "-resize" flag value of the form "WIDTHxHEIGHT" (e.g.
+// "512x512") into its width and height. Either dimension may be omitted
+// (e.g. "512x") to scale that axis proportionally to the other.
+func ParseResize(spec string) (width, height int, err error) {
+	w, h, ok := strings.Cut(spec, "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid -resize %q: expected WIDTHxHEIGHT", spec)
+	}
+	if w != "" {
+		width, err = strconv.Atoi(w)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid -resize width %q: %w", w, err)
+		}
+	}
+	if h != "" {
+		height, err = strconv.Atoi(h)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid -resize height %q: %w", h, err)
+		}
+	}
+	if width <= 0 && height <= 0 {
+		return 0, 0, fmt.Errorf("invalid -resize %q: at least one dimension is required", spec)
+	}
+	return width, height, nil
+}
+
+// ResizeStage returns a Stage that scales img to fit within width x height
+// while preserving its aspect ratio, using a Catmull-Rom (Lanczos-like)
+// resampler. A zero width or height is treated as unconstrained on that axis.
+func ResizeStage(width, height int) Stage {
+	return func(img image.Image) image.Image {
+		bounds := img.Bounds()
+		srcW, srcH := bounds.Dx(), bounds.Dy()
+		if srcW == 0 || srcH == 0 {
+			return img
+		}
+
+		dstW, dstH := width, height
+		switch {
+		case dstW <= 0 && dstH <= 0:
+			return img
+		case dstW <= 0:
+			dstW = int(math.Round(float64(srcW) * float64(dstH) / float64(srcH)))
+		case dstH <= 0:
+			dstH = int(math.Round(float64(srcH) * float64(dstW) / float64(srcW)))
+		default:
+			scale := math.Min(float64(dstW)/float64(srcW), float64(dstH)/float64(srcH))
+			dstW = int(math.Round(float64(srcW) * scale))
+			dstH = int(math.Round(float64(srcH) * scale))
+		}
+		if dstW < 1 {
+			dstW = 1
+		}
+		if dstH < 1 {
+			dstH = 1
+		}
+
+		dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+		return dst
+	}
+}
+
+// LuminanceStage returns a Stage that converts img to grayscale using the
+// Rec.709 luma weights (0.2126R + 0.7152G + 0.0722B), in place of the
+// ITU-R 601 weights color.GrayModel uses by default.
+func LuminanceStage() Stage {
+	return func(img image.Image) image.Image {
+		bounds := img.Bounds()
+		gray := image.NewGray(bounds)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				y709 := 0.2126*float64(r>>8) + 0.7152*float64(g>>8) + 0.0722*float64(b>>8)
+				gray.SetGray(x, y, color.Gray{Y: uint8(math.Round(y709))})
+			}
+		}
+		return gray
+	}
+}
+
+// ToneStage returns a Stage applying brightness, contrast, and gamma
+// adjustment to each color channel, in that order:
+//
+//	brightness: v' = v + brightness
+//	contrast:   v' = (v-128)*contrast + 128
+//	gamma:      v' = 255*(v/255)^(1/gamma)
+//
+// brightness of 0, contrast of 1, and gamma of 1 leave the image unchanged.
+func ToneStage(brightness, contrast, gamma float64) Stage {
+	adjust := func(v uint8) uint8 {
+		f := float64(v) + brightness
+		f = (f-128)*contrast + 128
+		f = math.Min(255, math.Max(0, f))
+		if gamma != 1 {
+			f = 255 * math.Pow(f/255, 1/gamma)
+		}
+		return uint8(math.Round(math.Min(255, math.Max(0, f))))
+	}
+
+	return func(img image.Image) image.Image {
+		bounds := img.Bounds()
+		out := image.NewRGBA(bounds)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, a := img.At(x, y).RGBA()
+				out.SetRGBA(x, y, color.RGBA{
+					R: adjust(uint8(r >> 8)),
+					G: adjust(uint8(g >> 8)),
+					B: adjust(uint8(b >> 8)),
+					A: uint8(a >> 8),
+				})
+			}
+		}
+		return out
+	}
+}
@@ -0,0 +1,24 @@
+package dither
+
+import "fmt"
+
+// ByName constructs a Ditherer from a CLI-style method name: atkinson,
+// floyd_steinberg, shtuki, sierra_lite, bayer, or blue_noise.
+func ByName(name string) (Ditherer, error) {
+	switch name {
+	case "atkinson":
+		return AtkinsonDitherer{}, nil
+	case "floyd_steinberg":
+		return FloydSteinbergDitherer{}, nil
+	case "shtuki":
+		return ShtukiDitherer{}, nil
+	case "sierra_lite":
+		return SierraLiteDitherer{}, nil
+	case "bayer":
+		return BayerDitherer{N: 8}, nil
+	case "blue_noise":
+		return BlueNoiseDitherer{Mask: DefaultBlueNoiseMask}, nil
+	default:
+		return nil, fmt.Errorf("unknown dithering method %q", name)
+	}
+}
@@ -0,0 +1,41 @@
+package imageio
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// SaveImage writes img to path, selecting the encoder from the file
+// extension: .png, .jpg/.jpeg, .gif (paletted, using img's own palette when
+// img is already an *image.Paletted), .bmp, or .tiff/.tif.
+func SaveImage(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer file.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		return png.Encode(file, img)
+	case ".jpg", ".jpeg":
+		return jpeg.Encode(file, img, nil)
+	case ".gif":
+		return gif.Encode(file, img, nil)
+	case ".bmp":
+		return bmp.Encode(file, img)
+	case ".tiff", ".tif":
+		return tiff.Encode(file, img, nil)
+	default:
+		return fmt.Errorf("unsupported output extension %q", ext)
+	}
+}
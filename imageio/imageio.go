@@ -0,0 +1,58 @@
+// Package imageio provides format-agnostic image loading and saving for the
+// dither CLI. It registers decoders for JPEG, PNG, GIF, BMP, TIFF, and WebP
+// so the dithering core in the dither package stays independent of any
+// particular image format.
+package imageio
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/rwcarlsen/goexif/exif"
+	_ "golang.org/x/image/webp" // registers the "webp" decoder
+)
+
+// LoadImage reads an image from path, auto-detecting its format from the
+// registered decoders (JPEG, PNG, GIF, BMP, TIFF, WebP). JPEG input with an
+// EXIF Orientation tag is automatically rotated/flipped so thumbnails shot
+// on phones come out right-side up.
+func LoadImage(path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	if format == "jpeg" {
+		if orientation, ok := jpegOrientation(data); ok {
+			img = applyOrientation(img, orientation)
+		}
+	}
+
+	return img, nil
+}
+
+// jpegOrientation reads the EXIF Orientation tag (1-8) from JPEG data, if
+// present. It reports ok=false if the data has no readable EXIF block or no
+// Orientation tag, which is the common case for screenshots and web images.
+func jpegOrientation(data []byte) (orientation int, ok bool) {
+	x, err := exif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, false
+	}
+	tag, err := x.Get(exif.Orientation)
+	if err != nil {
+		return 0, false
+	}
+	orientation, err = tag.Int(0)
+	if err != nil {
+		return 0, false
+	}
+	return orientation, true
+}
@@ -0,0 +1,65 @@
+package imageio
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// newMarkedImage builds a w x h RGBA image where At(x,y) is a distinct gray
+// value x+y*w, so a geometric transform's effect on pixel positions can be
+// checked exactly.
+func newMarkedImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.Gray{Y: uint8(x + y*w)})
+		}
+	}
+	return img
+}
+
+func grayAt(img image.Image, x, y int) uint8 {
+	return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+}
+
+func TestApplyOrientationNoOp(t *testing.T) {
+	img := newMarkedImage(3, 2)
+	out := applyOrientation(img, 1)
+	if out != image.Image(img) {
+		t.Error("orientation 1 should return the image unchanged")
+	}
+}
+
+func TestApplyOrientation6RotatesClockwise(t *testing.T) {
+	// A 3-wide, 2-tall image rotated 90 clockwise becomes 2-wide, 3-tall,
+	// with the original top-left pixel ending up in the top-right corner.
+	img := newMarkedImage(3, 2)
+	out := applyOrientation(img, 6)
+
+	b := out.Bounds()
+	if b.Dx() != 2 || b.Dy() != 3 {
+		t.Fatalf("orientation 6 bounds = %dx%d, want 2x3", b.Dx(), b.Dy())
+	}
+	if got, want := grayAt(out, 1, 0), grayAt(img, 0, 0); got != want {
+		t.Errorf("top-right pixel after orientation 6 = %d, want original top-left %d", got, want)
+	}
+}
+
+func TestApplyOrientation3Rotates180(t *testing.T) {
+	img := newMarkedImage(3, 2)
+	out := applyOrientation(img, 3)
+
+	if got, want := grayAt(out, 2, 1), grayAt(img, 0, 0); got != want {
+		t.Errorf("bottom-right pixel after orientation 3 = %d, want original top-left %d", got, want)
+	}
+}
+
+func TestApplyOrientation2FlipsHorizontal(t *testing.T) {
+	img := newMarkedImage(3, 2)
+	out := applyOrientation(img, 2)
+
+	if got, want := grayAt(out, 2, 0), grayAt(img, 0, 0); got != want {
+		t.Errorf("top-right pixel after orientation 2 = %d, want original top-left %d", got, want)
+	}
+}